@@ -0,0 +1,31 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"io"
+
+	"github.com/docker/docker/api/server/router"
+	"github.com/moby/go-archive"
+)
+
+// changesBackend is the part of the container backend that
+// getContainersChanges needs beyond the pre-existing ContainerChanges: a
+// streaming walk for the NDJSON format and a tar export for the tar format.
+// It's implemented by *daemon.Daemon (see ContainerChangesStream and
+// ContainerChangesTar in daemon/changes.go) and must be embedded into this
+// package's main Backend interface (backend.go) alongside ContainerChanges
+// for getContainersChanges to compile against the router's real backend.
+type changesBackend interface {
+	ContainerChangesStream(ctx context.Context, name string, onChange func(archive.Change) error) error
+	ContainerChangesTar(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// changesRoutes returns the route for GET /containers/{name}/changes. The
+// package's initRoutes (router.go) must append these to the route table it
+// builds for NewRouter, the same way it wires up every other container
+// route.
+func changesRoutes(r *containerRouter) []router.Route {
+	return []router.Route{
+		router.NewGetRoute("/containers/{name}/changes", r.getContainersChanges),
+	}
+}
@@ -0,0 +1,48 @@
+package container // import "github.com/docker/docker/api/server/router/container"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/docker/docker/api/server/httputils"
+	"github.com/moby/go-archive"
+)
+
+// getContainersChanges handles `GET /containers/{name}/changes`, registered
+// via changesRoutes (see container_changes_backend.go). The response format is
+// selected by the request's Accept header: the default "application/json"
+// returns the full change set as a single JSON array (as before),
+// "application/x-ndjson" streams one JSON-encoded archive.Change per line as
+// the daemon walks the container's filesystem, and "application/x-tar"
+// streams the changed paths as a tar archive whose entries carry the
+// contents of every added or modified regular file, produced via
+// archive.ExportChanges against the container's rootfs.
+func (s *containerRouter) getContainersChanges(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	name := vars["name"]
+
+	switch r.Header.Get("Accept") {
+	case "application/x-ndjson":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		return s.backend.ContainerChangesStream(ctx, name, func(c archive.Change) error {
+			return enc.Encode(c)
+		})
+	case "application/x-tar":
+		tarStream, err := s.backend.ContainerChangesTar(ctx, name)
+		if err != nil {
+			return err
+		}
+		defer tarStream.Close()
+		w.Header().Set("Content-Type", "application/x-tar")
+		_, err = io.Copy(w, tarStream)
+		return err
+	default:
+		changes, err := s.backend.ContainerChanges(ctx, name)
+		if err != nil {
+			return err
+		}
+		return httputils.WriteJSON(w, http.StatusOK, changes)
+	}
+}
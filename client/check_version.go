@@ -0,0 +1,66 @@
+package client
+
+import "context"
+
+// checkVersion is called by sendRequest (and therefore by every API method
+// that goes through it, e.g. Info, ContainerChangesStream) before a request
+// is built. When the client was constructed with WithAPIVersionNegotiation,
+// it lazily negotiates the API version on the first call.
+//
+// If the configured VersionNegotiator is a PrePingVersionNegotiator (e.g. one
+// built with CachedNegotiator) and already has a fresh cached version for
+// cli.host, that version is used directly and the daemon is never pinged -
+// this is what lets a short-lived CLI invocation skip the Ping round-trip
+// entirely instead of merely caching the result of a ping it already paid
+// for. Otherwise it falls back to pinging the daemon directly, rather than
+// delegating to NegotiateAPIVersion.
+//
+// Unlike NegotiateAPIVersion/NegotiateAPIVersionPing, a failure here is not
+// swallowed: the underlying ping/transport error is returned to the caller
+// instead of being silently ignored, so "daemon unreachable" surfaces as a
+// connection error rather than as a confusing "requires API version X, but
+// the Docker daemon API version is Y" message produced later from a stale
+// or default version.
+//
+// Callers that want the old best-effort behavior (keep negotiating lazily
+// but never fail the call itself because of it) can install
+// WithNegotiationErrorHandler to downgrade or suppress the error.
+func (cli *Client) checkVersion(ctx context.Context) error {
+	if !cli.negotiateVersion || cli.negotiated.Load() {
+		return nil
+	}
+
+	if !cli.manualOverride {
+		if cached, ok := cli.versionNegotiator.(PrePingVersionNegotiator); ok {
+			if v, ok := cached.NegotiateCached(ctx, cli.host, cli.version); ok {
+				cli.version = v
+				cli.negotiated.Store(true)
+				return nil
+			}
+		}
+	}
+
+	ping, err := cli.ping(ctx)
+	if err != nil {
+		if cli.negotiationErrorHandler != nil {
+			return cli.negotiationErrorHandler(err)
+		}
+		return err
+	}
+
+	cli.NegotiateAPIVersionPing(ping)
+	cli.negotiated.Store(true)
+	return nil
+}
+
+// WithNegotiationErrorHandler installs fn to transform (or suppress) an error
+// encountered while lazily negotiating the API version via checkVersion. fn
+// receives the underlying ping/transport error; returning nil restores the
+// pre-checkVersion best-effort behavior of proceeding with whatever version
+// the Client already had configured.
+func WithNegotiationErrorHandler(fn func(error) error) Opt {
+	return func(c *Client) error {
+		c.negotiationErrorHandler = fn
+		return nil
+	}
+}
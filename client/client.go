@@ -0,0 +1,112 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/crypto/ssh"
+)
+
+// Client is the API client that performs all operations against a Docker
+// daemon.
+type Client struct {
+	scheme string
+	host   string
+	proto  string
+	addr   string
+
+	client *http.Client
+
+	version        string
+	manualOverride bool
+
+	// negotiateVersion, when set by WithAPIVersionNegotiation, causes the
+	// client to lazily negotiate the API version against the daemon on
+	// the first request instead of using a fixed version.
+	negotiateVersion bool
+	// negotiated is set once checkVersion has successfully negotiated the
+	// API version, so later calls don't re-negotiate.
+	negotiated atomic.Bool
+	// negotiationErrorHandler, if set via WithNegotiationErrorHandler,
+	// lets callers downgrade a checkVersion negotiation failure instead
+	// of having it fail the in-flight request.
+	negotiationErrorHandler func(error) error
+	// versionNegotiator, if set via WithVersionNegotiator, replaces the
+	// built-in "downgrade to whatever the daemon reports" negotiation
+	// strategy used by NegotiateAPIVersion/NegotiateAPIVersionPing.
+	versionNegotiator VersionNegotiator
+
+	// sshConfig and sshControlMaster configure the ssh:// transport; see
+	// ssh.go. sshHelper is created by NewClientWithOpts for ssh:// hosts;
+	// its SSH connection is only established on the first dial.
+	sshConfig        *ssh.ClientConfig
+	sshControlMaster string
+	sshHelper        *sshConnHelper
+
+	// tracerProvider and requestLogger, if set via WithTracerProvider and
+	// WithRequestLogger, are consulted by sendRequest for every API call;
+	// see tracing.go.
+	tracerProvider trace.TracerProvider
+	requestLogger  func(RequestInfo)
+}
+
+// Opt is a configuration option passed to NewClientWithOpts to customize a
+// Client during construction.
+type Opt func(*Client) error
+
+// NewClientWithOpts creates a new API client with default values, then
+// applies ops in order.
+func NewClientWithOpts(ops ...Opt) (*Client, error) {
+	hostURL, err := ParseHostURL(defaultDockerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		host:    defaultDockerHost,
+		scheme:  hostURL.Scheme,
+		proto:   hostURL.Scheme,
+		addr:    hostURL.Host,
+		version: DefaultAPIVersion,
+		client:  &http.Client{CheckRedirect: CheckRedirect},
+	}
+
+	for _, op := range ops {
+		if err := op(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.proto == "ssh" && c.sshHelper == nil {
+		if err := c.configureSSHTransport(); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// ClientVersion returns the API version configured for the client. This may
+// have been fixed via WithVersion/DOCKER_API_VERSION, or negotiated against
+// a specific daemon via NegotiateAPIVersion/NegotiateAPIVersionPing.
+func (cli *Client) ClientVersion() string {
+	return cli.version
+}
+
+// DaemonHost returns the host address used by the client, in the same form
+// it was configured (e.g. via WithHost, FromEnv, or FromContext).
+func (cli *Client) DaemonHost() string {
+	return cli.host
+}
+
+// tlsConfig returns the TLS configuration of the client's underlying
+// http.Transport, or nil if the client isn't using TLS.
+func (cli *Client) tlsConfig() *tls.Config {
+	transport, ok := cli.client.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		return nil
+	}
+	return transport.TLSClientConfig
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"runtime"
@@ -465,6 +466,54 @@ func TestCustomAPIVersion(t *testing.T) {
 	}
 }
 
+// TestCheckVersionConnectionFailure asserts that checkVersion itself - the
+// pre-request negotiation path, not a real request - is what surfaces the
+// connection error: negotiated must still be false afterwards, so the next
+// call retries negotiation instead of using an unnegotiated version.
+func TestCheckVersionConnectionFailure(t *testing.T) {
+	client, err := NewClientWithOpts(
+		WithHost("tcp://no-such-host.invalid"),
+		WithAPIVersionNegotiation(),
+	)
+	assert.NilError(t, err)
+
+	err = client.checkVersion(context.Background())
+	assert.Check(t, err != nil)
+	var opErr *net.OpError
+	assert.Check(t, errors.As(err, &opErr), "expected a *net.OpError, got %T: %v", err, err)
+	assert.Check(t, !client.negotiated.Load(), "negotiated should remain false after a failed checkVersion")
+}
+
+// TestCheckVersionConnectionFailureThroughAPIMethod asserts that the same
+// failure surfaces end-to-end through a real API method (Info), unlike the
+// best-effort NegotiateAPIVersion, once lazy version negotiation is enabled.
+func TestCheckVersionConnectionFailureThroughAPIMethod(t *testing.T) {
+	client, err := NewClientWithOpts(
+		WithHost("tcp://no-such-host.invalid"),
+		WithAPIVersionNegotiation(),
+	)
+	assert.NilError(t, err)
+
+	_, err = client.Info(context.Background())
+	assert.Check(t, err != nil)
+	var opErr *net.OpError
+	assert.Check(t, errors.As(err, &opErr), "expected a *net.OpError, got %T: %v", err, err)
+}
+
+// TestCheckVersionWithNegotiationErrorHandler asserts that
+// WithNegotiationErrorHandler can downgrade a negotiation failure back to
+// the pre-checkVersion best-effort behavior.
+func TestCheckVersionWithNegotiationErrorHandler(t *testing.T) {
+	client, err := NewClientWithOpts(
+		WithHost("tcp://no-such-host.invalid"),
+		WithAPIVersionNegotiation(),
+		WithNegotiationErrorHandler(func(error) error { return nil }),
+	)
+	assert.NilError(t, err)
+
+	assert.Check(t, is.Nil(client.checkVersion(context.Background())))
+}
+
 type roundTripFunc func(*http.Request) (*http.Response, error)
 
 func (rtf roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/moby/moby/api/types/container"
+)
+
+// Container filesystem diff formats accepted by the daemon's
+// /containers/{name}/changes endpoint, selected via the Accept header.
+const (
+	changesAcceptJSON   = "application/json"
+	changesAcceptNDJSON = "application/x-ndjson"
+	changesAcceptTar    = "application/x-tar"
+)
+
+// ContainerChangesOptions holds the format selection for
+// ContainerChangesStream.
+type ContainerChangesOptions struct {
+	// Format selects how the daemon encodes the diff: ChangesFormatJSON
+	// (the default, a single JSON array), ChangesFormatNDJSON (one change
+	// per line, streamed as the daemon discovers it), or ChangesFormatTar
+	// (a tar stream of the changed/added file contents).
+	Format string
+}
+
+// Diff formats usable with ContainerChangesOptions.Format.
+const (
+	ChangesFormatJSON   = "json"
+	ChangesFormatNDJSON = "ndjson"
+	ChangesFormatTar    = "tar"
+)
+
+// ContainerChangesStream requests a container's filesystem diff and returns
+// the raw response body without buffering it, so the caller can decode or
+// copy it incrementally as the daemon streams it. The caller is responsible
+// for closing the returned ReadCloser.
+//
+// Use NewChangesDecoder to decode a ChangesFormatNDJSON stream one change at
+// a time, or copy a ChangesFormatTar stream directly to an archive.Untar (or
+// similar) consumer.
+func (cli *Client) ContainerChangesStream(ctx context.Context, containerID string, options ContainerChangesOptions) (io.ReadCloser, error) {
+	headers := http.Header{}
+	switch options.Format {
+	case ChangesFormatNDJSON:
+		headers.Set("Accept", changesAcceptNDJSON)
+	case ChangesFormatTar:
+		headers.Set("Accept", changesAcceptTar)
+	default:
+		headers.Set("Accept", changesAcceptJSON)
+	}
+
+	resp, err := cli.get(ctx, "/containers/"+containerID+"/changes", url.Values{}, headers)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// NewChangesDecoder returns a decoder that reads one container.FilesystemChange
+// at a time from a ChangesFormatNDJSON stream returned by
+// ContainerChangesStream.
+func NewChangesDecoder(r io.Reader) *ChangesDecoder {
+	return &ChangesDecoder{dec: json.NewDecoder(r)}
+}
+
+// ChangesDecoder decodes a newline-delimited stream of container filesystem
+// changes.
+type ChangesDecoder struct {
+	dec *json.Decoder
+}
+
+// Decode reads the next change off the stream. It returns io.EOF once the
+// stream is exhausted.
+func (d *ChangesDecoder) Decode() (container.FilesystemChange, error) {
+	var c container.FilesystemChange
+	err := d.dec.Decode(&c)
+	return c, err
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// dockerContextMetadata mirrors the subset of
+// ~/.docker/contexts/meta/<hash>/meta.json that the client needs to connect
+// to the endpoint a context describes. The on-disk format is produced by the
+// Docker CLI's context store and also carries description and orchestrator
+// metadata that the client has no use for; TLS material is not in meta.json
+// but in the context store's tls directory, which FromContext reads.
+type dockerContextMetadata struct {
+	Name      string `json:"Name"`
+	Endpoints map[string]struct {
+		Host          string `json:"Host"`
+		SkipTLSVerify bool   `json:"SkipTLSVerify"`
+	} `json:"Endpoints"`
+}
+
+// dockerConfig mirrors the subset of ~/.docker/config.json the client reads
+// to resolve the active context.
+type dockerConfig struct {
+	CurrentContext string `json:"currentContext"`
+}
+
+const dockerEndpoint = "docker"
+
+// FromContext is a functional option that configures host and TLS material
+// the same way the Docker CLI does when `docker --context <name>` (or
+// DOCKER_CONTEXT=<name>) is used: by reading
+// ~/.docker/contexts/meta/<hash>/meta.json, where <hash> is the sha256 of the
+// context name. An empty name resolves the currently active context via
+// CurrentContext.
+//
+// This lets library users target the same daemon their users have already
+// configured via `docker context create`, without reimplementing the context
+// store format themselves.
+func FromContext(name string) Opt {
+	return func(c *Client) error {
+		if name == "" {
+			resolved, err := CurrentContext()
+			if err != nil {
+				return err
+			}
+			name = resolved
+		}
+		if name == "" || name == "default" {
+			return FromEnv(c)
+		}
+
+		meta, err := loadContextMetadata(name)
+		if err != nil {
+			return fmt.Errorf("loading docker context %q: %w", name, err)
+		}
+
+		endpoint, ok := meta.Endpoints[dockerEndpoint]
+		if !ok {
+			return fmt.Errorf("docker context %q has no %q endpoint", name, dockerEndpoint)
+		}
+
+		if endpoint.Host != "" {
+			if err := WithHost(endpoint.Host)(c); err != nil {
+				return err
+			}
+		}
+
+		tlsDir := filepath.Join(contextsDir(), "tls", contextDirName(name), dockerEndpoint)
+		if info, statErr := os.Stat(tlsDir); statErr == nil && info.IsDir() {
+			ca := filepath.Join(tlsDir, "ca.pem")
+			cert := filepath.Join(tlsDir, "cert.pem")
+			key := filepath.Join(tlsDir, "key.pem")
+			if err := WithTLSClientConfig(ca, cert, key)(c); err != nil {
+				return err
+			}
+			if endpoint.SkipTLSVerify {
+				if tlsc := c.tlsConfig(); tlsc != nil {
+					tlsc.InsecureSkipVerify = true
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// CurrentContext resolves the name of the active Docker context, honoring
+// DOCKER_HOST and DOCKER_CONTEXT precedence exactly as the Docker CLI does:
+// DOCKER_HOST (if set) always wins and implies the "default" context,
+// DOCKER_CONTEXT overrides the persisted current context, and otherwise the
+// currentContext recorded in ~/.docker/config.json is used, defaulting to
+// "default".
+func CurrentContext() (string, error) {
+	if os.Getenv("DOCKER_HOST") != "" {
+		return "default", nil
+	}
+	if ctx := os.Getenv("DOCKER_CONTEXT"); ctx != "" {
+		return ctx, nil
+	}
+
+	cfg, err := loadDockerConfig()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "default", nil
+		}
+		return "", err
+	}
+	if cfg.CurrentContext == "" {
+		return "default", nil
+	}
+	return cfg.CurrentContext, nil
+}
+
+// ListContexts returns the names of every context in the context store,
+// including "default".
+func ListContexts() ([]string, error) {
+	names := []string{"default"}
+
+	metaDir := filepath.Join(contextsDir(), "meta")
+	entries, err := os.ReadDir(metaDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(metaDir, entry.Name(), "meta.json"))
+		if err != nil {
+			continue
+		}
+		var meta dockerContextMetadata
+		if err := json.Unmarshal(data, &meta); err != nil || meta.Name == "" {
+			continue
+		}
+		names = append(names, meta.Name)
+	}
+	return names, nil
+}
+
+func loadContextMetadata(name string) (*dockerContextMetadata, error) {
+	path := filepath.Join(contextsDir(), "meta", contextDirName(name), "meta.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var meta dockerContextMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func loadDockerConfig() (*dockerConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(home, ".docker", "config.json"))
+	if err != nil {
+		return nil, err
+	}
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// contextDirName returns the directory name the Docker CLI uses for a given
+// context name: the hex-encoded sha256 of the name.
+func contextDirName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+func contextsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "contexts")
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"runtime"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/skip"
+)
+
+// withTestContextsHome points $HOME at testdata/contexts/home, which
+// contains a single "test-context" context fixture, and clears the env vars
+// that would otherwise override context resolution.
+func withTestContextsHome(t *testing.T) {
+	t.Helper()
+	skip.If(t, runtime.GOOS == "windows")
+
+	t.Setenv("HOME", "testdata/contexts/home")
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_CONTEXT", "")
+}
+
+func TestCurrentContext(t *testing.T) {
+	withTestContextsHome(t)
+
+	name, err := CurrentContext()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(name, "test-context"))
+
+	t.Setenv("DOCKER_CONTEXT", "override-context")
+	name, err = CurrentContext()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(name, "override-context"))
+
+	t.Setenv("DOCKER_CONTEXT", "")
+	t.Setenv("DOCKER_HOST", "tcp://localhost:2375")
+	name, err = CurrentContext()
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(name, "default"))
+}
+
+func TestListContexts(t *testing.T) {
+	withTestContextsHome(t)
+
+	names, err := ListContexts()
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(names, "default"))
+	assert.Check(t, is.Contains(names, "test-context"))
+}
+
+func TestFromContext(t *testing.T) {
+	withTestContextsHome(t)
+
+	client, err := NewClientWithOpts(FromContext("test-context"))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(client.DaemonHost(), "tcp://testcontext.example.com:2376"))
+}
+
+func TestFromContextResolvesCurrent(t *testing.T) {
+	withTestContextsHome(t)
+
+	client, err := NewClientWithOpts(FromContext(""))
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(client.DaemonHost(), "tcp://testcontext.example.com:2376"))
+}
+
+func TestFromContextUnknown(t *testing.T) {
+	withTestContextsHome(t)
+
+	_, err := NewClientWithOpts(FromContext("does-not-exist"))
+	assert.Check(t, err != nil)
+}
@@ -0,0 +1,23 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrRedirect is the error returned by CheckRedirect when the client
+// refuses to follow a redirect for a non-GET request, since blindly
+// replaying POST/PUT/DELETE bodies against a different URL would be
+// unsafe.
+var ErrRedirect = errors.New("unexpected redirect in response")
+
+// CheckRedirect specifies the policy for dealing with redirect responses.
+// No redirect is followed: for GET requests the redirect response itself is
+// returned to the caller without an error (via http.ErrUseLastResponse),
+// while all other methods report ErrRedirect instead.
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if via[0].Method == http.MethodGet {
+		return http.ErrUseLastResponse
+	}
+	return ErrRedirect
+}
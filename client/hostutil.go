@@ -0,0 +1,33 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseHostURL parses a DOCKER_HOST-style address (e.g.
+// "unix:///var/run/docker.sock", "tcp://localhost:2376", "ssh://user@host")
+// into a url.URL, without validating that the scheme is one the client
+// actually knows how to dial.
+//
+// Unlike a generic URL, the entire remainder after "unix://" or "npipe://"
+// is treated as the host (these are filesystem paths, not host:port plus a
+// request path), while other schemes split at the first "/" into host and
+// path as usual.
+func ParseHostURL(host string) (*url.URL, error) {
+	proto, addr, ok := strings.Cut(host, "://")
+	if !ok || proto == "" || addr == "" {
+		return nil, fmt.Errorf("unable to parse docker host `%s`", host)
+	}
+
+	var basePath string
+	if proto != "unix" && proto != "npipe" {
+		if i := strings.Index(addr, "/"); i != -1 {
+			basePath = addr[i:]
+			addr = addr[:i]
+		}
+	}
+
+	return &url.URL{Scheme: proto, Host: addr, Path: basePath}, nil
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/moby/moby/api/types/system"
+)
+
+// Info returns daemon system-wide information, e.g. number of containers,
+// driver in use, etc. Like every other API method, it goes through get
+// (and so sendRequest), which lazily negotiates the API version via
+// checkVersion and records a tracing span / invokes the request logger for
+// this call.
+func (cli *Client) Info(ctx context.Context) (system.Info, error) {
+	var info system.Info
+
+	resp, err := cli.get(ctx, "/info", url.Values{}, nil)
+	defer ensureReaderClosed(resp)
+	if err != nil {
+		return info, err
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	return info, err
+}
@@ -0,0 +1,9 @@
+//go:build !docker_client_prometheus_metrics
+
+package client
+
+import "time"
+
+// observeRequestDuration is a no-op unless built with the
+// docker_client_prometheus_metrics build tag; see metrics_prometheus.go.
+func observeRequestDuration(operation string, statusCode int, elapsed time.Duration) {}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WithHost configures the client to connect to host, e.g.
+// "unix:///var/run/docker.sock", "tcp://localhost:2376", or
+// "ssh://user@host". For ssh:// hosts, the actual connection is wired up by
+// NewClientWithOpts after all options have run; see configureSSHTransport
+// in ssh.go.
+func WithHost(host string) Opt {
+	return func(c *Client) error {
+		hostURL, err := ParseHostURL(host)
+		if err != nil {
+			return err
+		}
+		c.host = host
+		c.scheme = hostURL.Scheme
+		c.proto = hostURL.Scheme
+		c.addr = hostURL.Host
+		return nil
+	}
+}
+
+// WithHTTPClient configures the *http.Client used to perform requests.
+func WithHTTPClient(client *http.Client) Opt {
+	return func(c *Client) error {
+		c.client = client
+		return nil
+	}
+}
+
+// WithVersion configures a fixed API version, disabling negotiation. A
+// leading "v" is stripped (as produced by e.g. `docker version --format`).
+// An empty version (after stripping "v") is equivalent to not calling
+// WithVersion at all: it resets the client to DefaultAPIVersion but leaves
+// negotiation enabled.
+func WithVersion(version string) Opt {
+	return func(c *Client) error {
+		version = strings.TrimPrefix(version, "v")
+		if version == "" {
+			c.version = DefaultAPIVersion
+			return nil
+		}
+		c.version = version
+		c.manualOverride = true
+		return nil
+	}
+}
+
+// WithVersionFromEnv configures the API version from the
+// EnvOverrideAPIVersion (DOCKER_API_VERSION) environment variable, if set.
+func WithVersionFromEnv() Opt {
+	return func(c *Client) error {
+		if v := os.Getenv(EnvOverrideAPIVersion); v != "" {
+			return WithVersion(v)(c)
+		}
+		return nil
+	}
+}
+
+// WithAPIVersionNegotiation enables lazy API version negotiation: the first
+// request made by the client pings the daemon (unless a
+// PrePingVersionNegotiator has a fresh cached version) and negotiates a
+// mutually supported version via NegotiateAPIVersionPing before proceeding.
+// Unlike NegotiateAPIVersion, a failed ping fails that request unless
+// WithNegotiationErrorHandler says otherwise.
+func WithAPIVersionNegotiation() Opt {
+	return func(c *Client) error {
+		c.negotiateVersion = true
+		return nil
+	}
+}
+
+// WithTLSClientConfig configures the client's transport to use mutual TLS
+// with the given CA, client certificate, and client key files. An empty
+// cacertPath leaves the system root pool in place (for use with, e.g.,
+// InsecureSkipVerify set afterwards).
+func WithTLSClientConfig(cacertPath, certPath, keyPath string) Opt {
+	return func(c *Client) error {
+		tlsc := &tls.Config{}
+
+		if certPath != "" || keyPath != "" {
+			cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+			if err != nil {
+				return fmt.Errorf("could not load X509 key pair: %w", err)
+			}
+			tlsc.Certificates = []tls.Certificate{cert}
+		}
+
+		if cacertPath != "" {
+			pem, err := os.ReadFile(cacertPath)
+			if err != nil {
+				return fmt.Errorf("could not read CA certificate: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return fmt.Errorf("could not parse CA certificate %q", cacertPath)
+			}
+			tlsc.RootCAs = pool
+		}
+
+		return WithHTTPClient(&http.Client{
+			CheckRedirect: CheckRedirect,
+			Transport:     &http.Transport{TLSClientConfig: tlsc},
+		})(c)
+	}
+}
+
+// FromEnv configures the client the same way the `docker` CLI does, from
+// DOCKER_HOST, DOCKER_API_VERSION, DOCKER_CERT_PATH, and DOCKER_TLS_VERIFY.
+func FromEnv(c *Client) error {
+	if host := os.Getenv("DOCKER_HOST"); host != "" {
+		if err := WithHost(host)(c); err != nil {
+			return err
+		}
+	}
+
+	if certPath := os.Getenv("DOCKER_CERT_PATH"); certPath != "" {
+		opt := WithTLSClientConfig(
+			filepath.Join(certPath, "ca.pem"),
+			filepath.Join(certPath, "cert.pem"),
+			filepath.Join(certPath, "key.pem"),
+		)
+		if err := opt(c); err != nil {
+			return err
+		}
+		if os.Getenv("DOCKER_TLS_VERIFY") == "" {
+			if tlsc := c.tlsConfig(); tlsc != nil {
+				tlsc.InsecureSkipVerify = true
+			}
+		}
+	}
+
+	return WithVersionFromEnv()(c)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/moby/moby/api/types"
+)
+
+// ping issues a raw request to the daemon's /_ping endpoint, bypassing
+// sendRequest (and therefore checkVersion) so it can be used both by
+// checkVersion itself and by the public NegotiateAPIVersion without
+// recursing.
+func (cli *Client) ping(ctx context.Context) (types.Ping, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cli.baseURL()+"/_ping", http.NoBody)
+	if err != nil {
+		return types.Ping{}, err
+	}
+
+	resp, err := cli.client.Do(req)
+	if err != nil {
+		return types.Ping{}, err
+	}
+	defer ensureReaderClosed(resp)
+
+	return types.Ping{APIVersion: resp.Header.Get("Api-Version")}, nil
+}
+
+// NegotiateAPIVersion queries the daemon's API and downgrades the client's
+// configured version to the highest version mutually supported, via
+// NegotiateAPIVersionPing. If the daemon can't be reached, the client's
+// version is left untouched and the connection error is swallowed: this
+// best-effort behavior is kept for compatibility with existing callers of
+// NegotiateAPIVersion. Code that wants a connection failure to surface as
+// an error should rely on checkVersion (run automatically before every
+// request when WithAPIVersionNegotiation is set) instead.
+func (cli *Client) NegotiateAPIVersion(ctx context.Context) error {
+	ping, err := cli.ping(ctx)
+	if err != nil {
+		return nil
+	}
+	cli.NegotiateAPIVersionPing(ping)
+	return nil
+}
+
+// NegotiateAPIVersionPing downgrades the client's configured API version to
+// the highest one mutually supported with the daemon, based on ping. If the
+// client was given an explicit version (via WithVersion or
+// DOCKER_API_VERSION), or a custom VersionNegotiator via
+// WithVersionNegotiator, that takes precedence over the built-in
+// downgrade-to-daemon-version behavior.
+func (cli *Client) NegotiateAPIVersionPing(ping types.Ping) {
+	if cli.manualOverride {
+		return
+	}
+
+	negotiator := cli.versionNegotiator
+	if negotiator == nil {
+		negotiator = NegotiateHighestMutual("", "")
+	}
+
+	if v, err := negotiator.Negotiate(context.Background(), cli.host, cli.version, ping.APIVersion); err == nil {
+		cli.version = v
+	}
+}
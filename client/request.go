@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// sendRequest is the single low-level entry point every API method goes
+// through (via get and its siblings). It is where the cross-cutting
+// concerns that apply to every request live: lazily negotiating the API
+// version before the request is built (checkVersion, see check_version.go),
+// opening a tracing span and invoking the request logger for the call
+// (startRequestSpan/injectTraceparent, see tracing.go), and building the
+// versioned request path (getAPIPath).
+func (cli *Client) sendRequest(ctx context.Context, method, path string, query url.Values, body io.Reader, headers http.Header) (*http.Response, error) {
+	if err := cli.checkVersion(ctx); err != nil {
+		return nil, err
+	}
+
+	apiPath := cli.getAPIPath(ctx, path, query)
+	ctx, end := cli.startRequestSpan(ctx, operationForPath(method, path), method, apiPath)
+
+	req, err := http.NewRequestWithContext(ctx, method, cli.baseURL()+apiPath, body)
+	if err != nil {
+		end(0, err)
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	cli.injectTraceparent(ctx, req.Header)
+
+	resp, err := cli.client.Do(req)
+	if err != nil {
+		end(0, err)
+		return nil, err
+	}
+	end(resp.StatusCode, nil)
+	return resp, nil
+}
+
+// get performs a GET request against path.
+func (cli *Client) get(ctx context.Context, path string, query url.Values, headers http.Header) (*http.Response, error) {
+	return cli.sendRequest(ctx, http.MethodGet, path, query, nil, headers)
+}
+
+// ensureReaderClosed drains and closes resp.Body so the underlying
+// connection can be reused even when the caller didn't read the full body,
+// e.g. on an error path.
+func ensureReaderClosed(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// getAPIPath builds the versioned request path for p, e.g.
+// "/containers/json" becomes "/v1.45/containers/json", encoding query as
+// the URL's query string.
+func (cli *Client) getAPIPath(_ context.Context, p string, query url.Values) string {
+	apiPath := p
+	if cli.version != "" {
+		apiPath = "/v" + cli.version + p
+	}
+
+	u := &url.URL{Path: apiPath}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// baseURL returns the scheme and host to use when building an absolute
+// request URL. For tcp/http/https hosts this is the configured host,
+// switching to https when the client's transport is using TLS. For every
+// other scheme (unix, npipe, ssh) a fixed dummy authority is used. For ssh
+// the transport installed by configureSSHTransport ignores that authority
+// and dials the multiplexed SSH session; WithHost installs no dialer for
+// unix or npipe sockets, so those connect only through a transport supplied
+// via WithHTTPClient.
+func (cli *Client) baseURL() string {
+	switch cli.proto {
+	case "tcp", "http", "https":
+		scheme := "http"
+		if cli.tlsConfig() != nil {
+			scheme = "https"
+		}
+		return scheme + "://" + cli.addr
+	default:
+		return "http://docker"
+	}
+}
+
+// operationForPath derives a tracing/logging operation name from a
+// request's method and path when the caller doesn't have a more specific
+// generated-method name to use.
+func operationForPath(method, path string) string {
+	return method + " " + path
+}
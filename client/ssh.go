@@ -0,0 +1,335 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kevinburke/ssh_config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// WithSSHConfig configures the ssh.ClientConfig used when DOCKER_HOST (or
+// WithHost) points at an ssh:// URL, instead of the default config derived
+// from ~/.ssh/config, ssh-agent, and the current user. Use this to pin a
+// specific IdentityFile, host key callback, or auth method.
+func WithSSHConfig(cfg *ssh.ClientConfig) Opt {
+	return func(c *Client) error {
+		c.sshConfig = cfg
+		return nil
+	}
+}
+
+// WithSSHControlMaster makes the ssh:// transport dial the unix socket at
+// path instead of a TCP connection to the host. The client still performs
+// its own SSH handshake and authentication over that socket; OpenSSH's
+// ControlMaster mux protocol is not spoken.
+func WithSSHControlMaster(path string) Opt {
+	return func(c *Client) error {
+		c.sshControlMaster = path
+		return nil
+	}
+}
+
+// sshConnHelper provides the DialContext (dialer) that sshConnHTTPTransport
+// installs for an ssh:// DOCKER_HOST. It opens a single golang.org/x/crypto/ssh connection per host and multiplexes
+// every HTTP round-trip over it by running `docker system dial-stdio` once
+// per logical connection and handing back a net.Conn backed by the SSH
+// session's stdin/stdout, instead of the historical approach of spawning a
+// new `ssh` subprocess (and re-authenticating) for every API call.
+type sshConnHelper struct {
+	addr   string
+	config *ssh.ClientConfig
+
+	// controlMaster, if set via WithSSHControlMaster, is the path to an
+	// existing unix socket to dial instead of addr. This only
+	// interoperates with a socket that still completes a normal SSH
+	// handshake over the connection it hands back; it does not speak
+	// OpenSSH's private mux protocol.
+	controlMaster string
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+func newSSHConnHelper(addr string, config *ssh.ClientConfig) *sshConnHelper {
+	return &sshConnHelper{addr: addr, config: config}
+}
+
+// defaultSSHClientConfig builds an ssh.ClientConfig for host the same way
+// the openssh `ssh` client would: resolving HostName/Port/User/IdentityFile
+// from ~/.ssh/config (falling back to the literal host if there's no config
+// or no matching entry) and authenticating via SSH_AUTH_SOCK if an agent is
+// running, plus the first IdentityFile the config names. portOverride, if
+// non-empty, takes precedence over both the ssh_config Port directive and
+// the "22" default, for an explicit ssh://host:port in DOCKER_HOST/WithHost.
+func defaultSSHClientConfig(host, portOverride string) (addr string, user string, config *ssh.ClientConfig, err error) {
+	cfg, cfgErr := loadSSHConfig()
+	if cfgErr != nil {
+		cfg = &ssh_config.Config{}
+	}
+
+	hostname := firstNonEmpty(cfg.Get(host, "HostName"), host)
+	port := firstNonEmpty(portOverride, cfg.Get(host, "Port"), "22")
+	user = firstNonEmpty(cfg.Get(host, "User"), os.Getenv("USER"))
+
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, dialErr := net.Dial("unix", sock); dialErr == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	if identity := cfg.Get(host, "IdentityFile"); identity != "" {
+		if signer, keyErr := loadIdentityFile(expandHome(identity)); keyErr == nil {
+			methods = append(methods, ssh.PublicKeys(signer))
+		}
+	}
+
+	return net.JoinHostPort(hostname, port), user, &ssh.ClientConfig{
+		User:            user,
+		Auth:            methods,
+		HostKeyCallback: defaultHostKeyCallback(),
+	}, nil
+}
+
+// defaultHostKeyCallback returns a callback that verifies the daemon's host
+// key against ~/.ssh/known_hosts, the same file the `ssh` subprocess this
+// transport replaces checks. If known_hosts can't be loaded (missing file,
+// permissions, parse error), it falls back to rejecting every host key:
+// callers that need a different trust model (e.g. TOFU, or a custom
+// known_hosts path) should pass their own HostKeyCallback via WithSSHConfig.
+func defaultHostKeyCallback() ssh.HostKeyCallback {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return rejectHostKey
+	}
+	cb, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+	if err != nil {
+		return rejectHostKey
+	}
+	return cb
+}
+
+func rejectHostKey(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	return fmt.Errorf("no known_hosts file available to verify host key for %s", hostname)
+}
+
+func loadSSHConfig() (*ssh_config.Config, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(home, ".ssh", "config"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ssh_config.Decode(f)
+}
+
+func loadIdentityFile(path string) (ssh.Signer, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ssh.ParsePrivateKey(key)
+}
+
+func expandHome(path string) string {
+	if !filepath.IsAbs(path) && len(path) > 1 && path[:2] == "~/" {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// dialer returns a func usable as http.Transport.DialContext: each call opens
+// a new logical stream (an SSH session running `docker system dial-stdio`)
+// over the shared, lazily-established SSH connection, so concurrent
+// round-trips don't each pay a fresh TCP+SSH handshake.
+func (h *sshConnHelper) dialer(ctx context.Context, _, _ string) (net.Conn, error) {
+	client, err := h.sharedClient()
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := client.NewSession()
+	if err != nil {
+		// The shared connection may have gone stale (e.g. the remote
+		// end closed it); drop it so the next dial re-establishes.
+		h.mu.Lock()
+		if h.client == client {
+			h.client = nil
+		}
+		h.mu.Unlock()
+		return nil, fmt.Errorf("opening ssh session: %w", err)
+	}
+
+	in, err := session.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := session.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Start("docker system dial-stdio"); err != nil {
+		return nil, fmt.Errorf("starting dial-stdio over ssh: %w", err)
+	}
+
+	return &sshSessionConn{session: session, in: in, out: out}, nil
+}
+
+func (h *sshConnHelper) sharedClient() (*ssh.Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.client != nil {
+		return h.client, nil
+	}
+
+	if h.controlMaster != "" {
+		conn, err := net.Dial("unix", h.controlMaster)
+		if err != nil {
+			return nil, fmt.Errorf("dialing ssh control master %s: %w", h.controlMaster, err)
+		}
+		sshConn, chans, reqs, err := ssh.NewClientConn(conn, h.addr, h.config)
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("handshaking over ssh control master %s: %w", h.controlMaster, err)
+		}
+		h.client = ssh.NewClient(sshConn, chans, reqs)
+		return h.client, nil
+	}
+
+	client, err := ssh.Dial("tcp", h.addr, h.config)
+	if err != nil {
+		return nil, fmt.Errorf("dialing ssh host %s: %w", h.addr, err)
+	}
+	h.client = client
+	return client, nil
+}
+
+// Close tears down the shared SSH connection, if one was established. It is
+// safe to call more than once.
+func (h *sshConnHelper) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.client == nil {
+		return nil
+	}
+	err := h.client.Close()
+	h.client = nil
+	return err
+}
+
+// configureSSHTransport wires an ssh:// host (selected via WithHost, FromEnv,
+// or FromContext) up to an actual connection: it builds a sshConnHelper -
+// using c.sshConfig if WithSSHConfig configured one, otherwise
+// defaultSSHClientConfig's ~/.ssh/config-and-agent-derived config - and
+// installs sshConnHTTPTransport(helper) as the client's http.Transport.
+// NewClientWithOpts calls this once all options have run, so it sees the
+// final c.host/c.sshConfig/c.sshControlMaster.
+func (c *Client) configureSSHTransport() error {
+	urlUser, hostname, urlPort := splitSSHAuthority(c.addr)
+
+	addr, _, config, err := defaultSSHClientConfig(hostname, urlPort)
+	if err != nil {
+		return fmt.Errorf("configuring ssh transport for %s: %w", c.host, err)
+	}
+	if c.sshConfig != nil {
+		config = c.sshConfig
+	} else if urlUser != "" {
+		// An explicit user@host in DOCKER_HOST/WithHost overrides whatever
+		// ~/.ssh/config or $USER would otherwise have produced, matching
+		// how the `ssh` command line itself takes precedence.
+		config.User = urlUser
+	}
+
+	helper := newSSHConnHelper(addr, config)
+	helper.controlMaster = c.sshControlMaster
+
+	c.sshHelper = helper
+	c.client = &http.Client{
+		CheckRedirect: CheckRedirect,
+		Transport:     sshConnHTTPTransport(helper),
+	}
+	return nil
+}
+
+// splitSSHAuthority pulls the "user", "host", and "port" components out of
+// an ssh:// URL's host component (e.g. "user@host:2222"), so the hostname
+// alone can be looked up as a Host pattern in ~/.ssh/config the same way the
+// `ssh` command line argument would be, while user and port (when present)
+// still take precedence over whatever that lookup produces.
+func splitSSHAuthority(addr string) (user, hostname, port string) {
+	u, err := url.Parse("ssh://" + addr)
+	if err != nil || u.Hostname() == "" {
+		return "", addr, ""
+	}
+	if u.User != nil {
+		user = u.User.Username()
+	}
+	return user, u.Hostname(), u.Port()
+}
+
+// sshConnHTTPTransport builds an *http.Transport that multiplexes every
+// request over a single SSH connection via h.dialer, for use as a Client's
+// underlying http.Client.Transport when WithHost selects an ssh:// URL.
+func sshConnHTTPTransport(h *sshConnHelper) *http.Transport {
+	return &http.Transport{
+		DialContext: h.dialer,
+		// A single remote `docker system dial-stdio` session only ever
+		// carries one logical stream, so each round-trip needs its own
+		// session; nothing is gained by keeping idle ones around.
+		DisableKeepAlives: true,
+	}
+}
+
+// sshSessionConn adapts an *ssh.Session running `docker system dial-stdio`
+// to a net.Conn, so it can be used as the transport for an http.Client.
+// Deadlines are accepted but not enforced: the underlying SSH channel has no
+// notion of read/write deadlines, matching the behavior of the `ssh`
+// subprocess pipe it replaces.
+type sshSessionConn struct {
+	session *ssh.Session
+	in      interface {
+		Write([]byte) (int, error)
+	}
+	out interface {
+		Read([]byte) (int, error)
+	}
+}
+
+func (c *sshSessionConn) Read(b []byte) (int, error)  { return c.out.Read(b) }
+func (c *sshSessionConn) Write(b []byte) (int, error) { return c.in.Write(b) }
+func (c *sshSessionConn) Close() error                { return c.session.Close() }
+
+func (c *sshSessionConn) LocalAddr() net.Addr              { return sshAddr{} }
+func (c *sshSessionConn) RemoteAddr() net.Addr             { return sshAddr{} }
+func (c *sshSessionConn) SetDeadline(time.Time) error      { return nil }
+func (c *sshSessionConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *sshSessionConn) SetWriteDeadline(time.Time) error { return nil }
+
+type sshAddr struct{}
+
+func (sshAddr) Network() string { return "ssh" }
+func (sshAddr) String() string  { return "ssh" }
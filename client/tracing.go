@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestInfo describes a single API call, passed to a WithRequestLogger
+// callback after the request has completed (successfully or not).
+type RequestInfo struct {
+	// Operation is the HTTP method and unversioned request path, e.g.
+	// "GET /containers/json" (see operationForPath).
+	Operation string
+	// Method and Path are the HTTP method and versioned request path,
+	// e.g. "GET" and "/v1.45/containers/json".
+	Method string
+	Path   string
+	// APIVersion is the negotiated API version used for this request.
+	APIVersion string
+	// Negotiated reports whether APIVersion came from version negotiation
+	// rather than being fixed by WithVersion.
+	Negotiated bool
+	// StatusCode is the HTTP status code of the response, or 0 if the
+	// request never received one.
+	StatusCode int
+	// Err is the error returned to the caller, if any.
+	Err error
+}
+
+// WithTracerProvider configures an OpenTelemetry TracerProvider used to
+// create a span named "docker.client.<operation>" around every API call,
+// with a W3C traceparent header injected into the outgoing request so a
+// correspondingly instrumented daemon can join the trace.
+func WithTracerProvider(tp trace.TracerProvider) Opt {
+	return func(c *Client) error {
+		c.tracerProvider = tp
+		return nil
+	}
+}
+
+// WithRequestLogger registers fn to be called with a RequestInfo after every
+// API call completes. Unlike tracing spans, this runs synchronously and with
+// no external dependency, making it suitable for simple structured logging
+// or metrics that don't need a full tracing backend.
+func WithRequestLogger(fn func(RequestInfo)) Opt {
+	return func(c *Client) error {
+		c.requestLogger = fn
+		return nil
+	}
+}
+
+// startRequestSpan opens a span for operation (if a TracerProvider was
+// configured) and returns a func, to be called once the request completes,
+// that ends the span, records err on it, and invokes the configured request
+// logger. It is called from sendRequest for every API call.
+func (cli *Client) startRequestSpan(ctx context.Context, operation, method, path string) (context.Context, func(statusCode int, err error)) {
+	start := time.Now()
+	info := RequestInfo{
+		Operation:  operation,
+		Method:     method,
+		Path:       path,
+		APIVersion: cli.ClientVersion(),
+		Negotiated: cli.negotiated.Load() && !cli.manualOverride,
+	}
+
+	var span trace.Span
+	if cli.tracerProvider != nil {
+		tracer := cli.tracerProvider.Tracer("github.com/moby/moby/client")
+		ctx, span = tracer.Start(ctx, "docker.client."+operation, trace.WithAttributes(
+			httpMethodAttr(method),
+			urlPathAttr(path),
+			apiVersionAttr(info.APIVersion),
+		))
+	}
+
+	return ctx, func(statusCode int, err error) {
+		info.StatusCode = statusCode
+		info.Err = err
+
+		if span != nil {
+			span.SetAttributes(httpStatusCodeAttr(statusCode), negotiatedAttr(info.Negotiated))
+			if err != nil {
+				span.RecordError(err)
+			}
+			span.End()
+		}
+		observeRequestDuration(operation, statusCode, time.Since(start))
+
+		if cli.requestLogger != nil {
+			cli.requestLogger(info)
+		}
+	}
+}
+
+// injectTraceparent adds a W3C traceparent header (and tracestate, if any)
+// derived from ctx's span context using the W3C TraceContext propagator, so a
+// correspondingly instrumented daemon can join the client's trace.
+func (cli *Client) injectTraceparent(ctx context.Context, header http.Header) {
+	if cli.tracerProvider == nil {
+		return
+	}
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(header))
+}
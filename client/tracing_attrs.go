@@ -0,0 +1,23 @@
+package client
+
+import "go.opentelemetry.io/otel/attribute"
+
+func httpMethodAttr(method string) attribute.KeyValue {
+	return attribute.String("http.method", method)
+}
+
+func urlPathAttr(path string) attribute.KeyValue {
+	return attribute.String("url.path", path)
+}
+
+func apiVersionAttr(version string) attribute.KeyValue {
+	return attribute.String("docker.api_version", version)
+}
+
+func httpStatusCodeAttr(statusCode int) attribute.KeyValue {
+	return attribute.Int("http.status_code", statusCode)
+}
+
+func negotiatedAttr(negotiated bool) attribute.KeyValue {
+	return attribute.Bool("docker.negotiated", negotiated)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestStartRequestSpanRecordsAttributesAndLogger(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var logged RequestInfo
+	client, err := NewClientWithOpts(
+		WithTracerProvider(tp),
+		WithRequestLogger(func(info RequestInfo) { logged = info }),
+	)
+	assert.NilError(t, err)
+
+	_, end := client.startRequestSpan(context.Background(), "ContainerList", "GET", "/v1.45/containers/json")
+	end(200, nil)
+
+	assert.Check(t, is.Equal(logged.Operation, "ContainerList"))
+	assert.Check(t, is.Equal(logged.StatusCode, 200))
+	assert.Check(t, is.Nil(logged.Err))
+
+	spans := exporter.GetSpans()
+	assert.Check(t, is.Len(spans, 1))
+	assert.Check(t, is.Equal(spans[0].Name, "docker.client.ContainerList"))
+}
+
+// TestInfoRecordsSpanAndInjectsTraceparent asserts that a real API call
+// going through sendRequest - not just a direct startRequestSpan call -
+// opens a span, injects a traceparent header into the outgoing request, and
+// invokes the configured request logger.
+func TestInfoRecordsSpanAndInjectsTraceparent(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	var gotTraceparent string
+	var logged RequestInfo
+	client, err := NewClientWithOpts(
+		WithTracerProvider(tp),
+		WithRequestLogger(func(info RequestInfo) { logged = info }),
+		WithHTTPClient(&http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				gotTraceparent = req.Header.Get("traceparent")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader("{}")),
+				}, nil
+			}),
+		}),
+	)
+	assert.NilError(t, err)
+
+	_, err = client.Info(context.Background())
+	assert.NilError(t, err)
+
+	assert.Check(t, gotTraceparent != "", "expected a traceparent header to be injected")
+	assert.Check(t, is.Equal(logged.Operation, "GET /info"))
+	assert.Check(t, is.Equal(logged.StatusCode, http.StatusOK))
+
+	spans := exporter.GetSpans()
+	assert.Check(t, is.Len(spans, 1))
+	assert.Check(t, is.Equal(spans[0].Name, "docker.client.GET /info"))
+}
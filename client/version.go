@@ -0,0 +1,15 @@
+package client
+
+// DefaultAPIVersion is the version of the API used by the client when no
+// custom version is configured via WithVersion, WithVersionFromEnv, or
+// negotiation against a specific daemon.
+const DefaultAPIVersion = "1.51"
+
+// EnvOverrideAPIVersion is the name of the environment variable that can be
+// used to override the API version used by the client, as consulted by
+// WithVersionFromEnv and FromEnv.
+const EnvOverrideAPIVersion = "DOCKER_API_VERSION"
+
+// defaultDockerHost is used when no host is configured via WithHost,
+// FromEnv, or FromContext.
+const defaultDockerHost = "unix:///var/run/docker.sock"
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moby/moby/api/types/versions"
+)
+
+// fallbackAPIVersion is the last API version before version negotiation was
+// added, used when the daemon doesn't report a version at all.
+const fallbackAPIVersion = "1.24"
+
+// VersionNegotiator decides which API version a Client should use for a
+// given daemon. It is consulted by Client.NegotiateAPIVersion and
+// Client.NegotiateAPIVersionPing in place of the built-in "downgrade to
+// whatever the daemon reports" behavior.
+type VersionNegotiator interface {
+	// Negotiate returns the API version to use given the daemon host being
+	// connected to, the client's configured version (may be empty), and the
+	// version reported by the daemon's ping response (may be empty if the
+	// daemon predates API-version reporting).
+	Negotiate(ctx context.Context, host, clientVersion, pingVersion string) (string, error)
+}
+
+// PrePingVersionNegotiator is implemented by a VersionNegotiator that can
+// answer from a prior negotiation without needing the daemon's ping
+// response, letting Client.checkVersion skip the Ping round-trip entirely on
+// a cache hit instead of only caching the result of a ping it already paid
+// for. CachedNegotiator implements this.
+type PrePingVersionNegotiator interface {
+	VersionNegotiator
+
+	// NegotiateCached returns a previously negotiated version for host, and
+	// whether one was found and is still fresh. It never pings the daemon.
+	NegotiateCached(ctx context.Context, host, clientVersion string) (version string, ok bool)
+}
+
+// FixedVersion is a VersionNegotiator that always returns the same version,
+// ignoring whatever the daemon reports. It is equivalent to configuring a
+// Client with WithVersion and never negotiating.
+type FixedVersion string
+
+// Negotiate implements VersionNegotiator.
+func (f FixedVersion) Negotiate(_ context.Context, _, _, _ string) (string, error) {
+	return string(f), nil
+}
+
+// NegotiateHighestMutual returns a VersionNegotiator that behaves like the
+// built-in negotiation (downgrade to the daemon's reported version, or to
+// the last pre-negotiation version if the daemon doesn't report one) but
+// clamps the result to [minVersion, maxVersion]. Use it when your code
+// depends on features from a minimum API version and would rather fail
+// clearly than silently negotiate down below it.
+func NegotiateHighestMutual(minVersion, maxVersion string) VersionNegotiator {
+	return highestMutualNegotiator{min: minVersion, max: maxVersion}
+}
+
+type highestMutualNegotiator struct {
+	min, max string
+}
+
+func (n highestMutualNegotiator) Negotiate(_ context.Context, _, clientVersion, pingVersion string) (string, error) {
+	negotiated := pingVersion
+	if negotiated == "" {
+		negotiated = fallbackAPIVersion
+	}
+	if clientVersion != "" && versions.LessThan(clientVersion, negotiated) {
+		negotiated = clientVersion
+	}
+	if n.min != "" && versions.LessThan(negotiated, n.min) {
+		negotiated = n.min
+	}
+	if n.max != "" && versions.LessThan(n.max, negotiated) {
+		negotiated = n.max
+	}
+	return negotiated, nil
+}
+
+// NegotiationStore persists a negotiated API version across process
+// restarts, keyed by a string. CachedNegotiator uses the daemon host as the
+// key; no daemon fingerprint is part of it, so a different daemon reached at
+// the same host address is handed the same entry.
+type NegotiationStore interface {
+	// Load returns the previously stored version for key, and whether an
+	// entry was found.
+	Load(key string) (version string, ok bool)
+	// Save records version for key.
+	Save(key, version string) error
+}
+
+// InMemoryNegotiationStore is a NegotiationStore backed by a map, useful in
+// tests or for processes that only want to cache negotiation within their
+// own lifetime.
+type InMemoryNegotiationStore struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// NewInMemoryNegotiationStore returns an empty InMemoryNegotiationStore.
+func NewInMemoryNegotiationStore() *InMemoryNegotiationStore {
+	return &InMemoryNegotiationStore{entries: make(map[string]string)}
+}
+
+// Load implements NegotiationStore.
+func (s *InMemoryNegotiationStore) Load(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.entries[key]
+	return v, ok
+}
+
+// Save implements NegotiationStore.
+func (s *InMemoryNegotiationStore) Save(key, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = version
+	return nil
+}
+
+// CachedNegotiator wraps another VersionNegotiator and persists its result in
+// store for ttl, keyed by the daemon host (so a cached entry for one host is
+// never handed to a different host). It implements PrePingVersionNegotiator,
+// so Client.checkVersion can call NegotiateCached and, on a hit, skip the
+// Ping round-trip entirely - the way short-lived CLI invocations avoid
+// paying a Ping on every run, including their very first one in a new
+// process, since freshness is determined from a timestamp persisted
+// alongside the version in store rather than from any in-process state.
+func CachedNegotiator(ttl time.Duration, store NegotiationStore, next VersionNegotiator) VersionNegotiator {
+	return &cachedNegotiator{ttl: ttl, store: store, next: next}
+}
+
+type cachedNegotiator struct {
+	ttl   time.Duration
+	store NegotiationStore
+	next  VersionNegotiator
+}
+
+// NegotiateCached implements PrePingVersionNegotiator. clientVersion is
+// accepted for symmetry with Negotiate but isn't part of the cache key: the
+// cached version already reflects whatever clientVersion was negotiated
+// against when it was stored.
+func (c *cachedNegotiator) NegotiateCached(_ context.Context, host, _ string) (string, bool) {
+	raw, ok := c.store.Load(host)
+	if !ok {
+		return "", false
+	}
+	version, storedAt, ok := decodeCacheEntry(raw)
+	if !ok || time.Since(storedAt) >= c.ttl {
+		return "", false
+	}
+	return version, true
+}
+
+// Negotiate implements VersionNegotiator. It returns the cached version for
+// host if a fresh one is stored, even when the caller already paid for a
+// ping; otherwise it negotiates against pingVersion via the wrapped
+// negotiator and stores the result for next time.
+func (c *cachedNegotiator) Negotiate(ctx context.Context, host, clientVersion, pingVersion string) (string, error) {
+	if version, ok := c.NegotiateCached(ctx, host, clientVersion); ok {
+		return version, nil
+	}
+
+	negotiated, err := c.next.Negotiate(ctx, host, clientVersion, pingVersion)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.store.Save(host, encodeCacheEntry(negotiated, time.Now())); err != nil {
+		return "", err
+	}
+	return negotiated, nil
+}
+
+// encodeCacheEntry/decodeCacheEntry pack a negotiated version together with
+// the time it was negotiated into the single string NegotiationStore
+// stores, so TTL freshness can be checked from the store's contents alone -
+// necessary for the cache to be useful across process restarts, when there
+// is no in-memory state to consult.
+func encodeCacheEntry(version string, at time.Time) string {
+	return strconv.FormatInt(at.Unix(), 10) + "|" + version
+}
+
+func decodeCacheEntry(raw string) (version string, storedAt time.Time, ok bool) {
+	unixSeconds, version, found := strings.Cut(raw, "|")
+	if !found {
+		return "", time.Time{}, false
+	}
+	sec, err := strconv.ParseInt(unixSeconds, 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return version, time.Unix(sec, 0), true
+}
+
+// WithVersionNegotiator configures a Client to delegate API-version
+// negotiation to n instead of the built-in downgrade-to-daemon-version
+// behavior used by NegotiateAPIVersion and NegotiateAPIVersionPing.
+func WithVersionNegotiator(n VersionNegotiator) Opt {
+	return func(c *Client) error {
+		c.versionNegotiator = n
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileNegotiationStore is a NegotiationStore backed by a JSON file on disk,
+// letting short-lived CLI processes reuse a negotiated API version across
+// invocations instead of paying a Ping on every run.
+type FileNegotiationStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileNegotiationStore returns a FileNegotiationStore persisting entries
+// to path. The file and its parent directory are created on first Save if
+// they don't already exist.
+func NewFileNegotiationStore(path string) *FileNegotiationStore {
+	return &FileNegotiationStore{path: path}
+}
+
+// Load implements NegotiationStore.
+func (s *FileNegotiationStore) Load(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readLocked()
+	if err != nil {
+		return "", false
+	}
+	v, ok := entries[key]
+	return v, ok
+}
+
+// Save implements NegotiationStore.
+func (s *FileNegotiationStore) Save(key, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readLocked()
+	if err != nil {
+		entries = map[string]string{}
+	}
+	entries[key] = version
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+func (s *FileNegotiationStore) readLocked() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	entries := map[string]string{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
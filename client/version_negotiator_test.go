@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestFixedVersionNegotiate(t *testing.T) {
+	n := FixedVersion("1.41")
+	v, err := n.Negotiate(context.Background(), "tcp://daemon-a:2376", "1.20", "1.50")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v, "1.41"))
+}
+
+func TestNegotiateHighestMutual(t *testing.T) {
+	tests := []struct {
+		doc                          string
+		min, max, client, ping, want string
+	}{
+		{doc: "within range", min: "1.41", max: "1.47", ping: "1.44", want: "1.44"},
+		{doc: "clamped to min", min: "1.41", max: "1.47", ping: "1.30", want: "1.41"},
+		{doc: "clamped to max", min: "1.41", max: "1.47", ping: "1.50", want: "1.47"},
+		{doc: "no daemon version falls back then clamps", min: "1.41", max: "1.47", ping: "", want: "1.41"},
+		{doc: "client override still respected", min: "1.30", max: "1.47", client: "1.35", ping: "1.44", want: "1.35"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.doc, func(t *testing.T) {
+			n := NegotiateHighestMutual(tc.min, tc.max)
+			v, err := n.Negotiate(context.Background(), "tcp://daemon-a:2376", tc.client, tc.ping)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(v, tc.want))
+		})
+	}
+}
+
+func TestCachedNegotiator(t *testing.T) {
+	calls := 0
+	inner := negotiatorFunc(func(_ context.Context, _, _, pingVersion string) (string, error) {
+		calls++
+		return pingVersion, nil
+	})
+
+	store := NewInMemoryNegotiationStore()
+	cached := CachedNegotiator(time.Minute, store, inner)
+
+	v, err := cached.Negotiate(context.Background(), "tcp://daemon-a:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v, "1.44"))
+	assert.Check(t, is.Equal(calls, 1))
+
+	// Second call for the same host should be served from cache.
+	v, err = cached.Negotiate(context.Background(), "tcp://daemon-a:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v, "1.44"))
+	assert.Check(t, is.Equal(calls, 1))
+}
+
+// TestCachedNegotiatorKeyedByHost asserts that a cached entry for one daemon
+// host is never handed to a different one, even though both negotiations
+// happened against the same pingVersion.
+func TestCachedNegotiatorKeyedByHost(t *testing.T) {
+	calls := 0
+	inner := negotiatorFunc(func(_ context.Context, _, _, pingVersion string) (string, error) {
+		calls++
+		return pingVersion, nil
+	})
+
+	store := NewInMemoryNegotiationStore()
+	cached := CachedNegotiator(time.Minute, store, inner)
+
+	_, err := cached.Negotiate(context.Background(), "tcp://daemon-a:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(calls, 1))
+
+	// A different host must not be served daemon-a's cached entry.
+	_, err = cached.Negotiate(context.Background(), "tcp://daemon-b:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(calls, 2))
+}
+
+// TestCachedNegotiatorPersistsAcrossRestarts asserts that a fresh
+// cachedNegotiator instance (as would exist in a new process) still hits the
+// store instead of re-negotiating, since freshness is tracked by a
+// timestamp persisted in the store rather than in-process state.
+func TestCachedNegotiatorPersistsAcrossRestarts(t *testing.T) {
+	calls := 0
+	inner := negotiatorFunc(func(_ context.Context, _, _, pingVersion string) (string, error) {
+		calls++
+		return pingVersion, nil
+	})
+
+	store := NewInMemoryNegotiationStore()
+
+	first := CachedNegotiator(time.Minute, store, inner)
+	v, err := first.Negotiate(context.Background(), "tcp://daemon-a:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v, "1.44"))
+	assert.Check(t, is.Equal(calls, 1))
+
+	// A brand new negotiator sharing the same store, as after a process
+	// restart, should still get a cache hit.
+	second := CachedNegotiator(time.Minute, store, inner)
+	v, err = second.Negotiate(context.Background(), "tcp://daemon-a:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(v, "1.44"))
+	assert.Check(t, is.Equal(calls, 1))
+}
+
+// TestCachedNegotiatorNegotiateCachedSkipsNext asserts that NegotiateCached -
+// the method checkVersion calls before pinging the daemon - never invokes
+// the wrapped negotiator, so a warm cache genuinely avoids the round-trip
+// that produces pingVersion in the first place, rather than only caching the
+// result of a ping checkVersion already paid for.
+func TestCachedNegotiatorNegotiateCachedSkipsNext(t *testing.T) {
+	calls := 0
+	inner := negotiatorFunc(func(_ context.Context, _, _, pingVersion string) (string, error) {
+		calls++
+		return pingVersion, nil
+	})
+
+	store := NewInMemoryNegotiationStore()
+	cached := CachedNegotiator(time.Minute, store, inner).(PrePingVersionNegotiator)
+
+	_, ok := cached.NegotiateCached(context.Background(), "tcp://daemon-a:2376", "")
+	assert.Check(t, !ok)
+	assert.Check(t, is.Equal(calls, 0))
+
+	_, err := cached.Negotiate(context.Background(), "tcp://daemon-a:2376", "", "1.44")
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(calls, 1))
+
+	v, ok := cached.NegotiateCached(context.Background(), "tcp://daemon-a:2376", "")
+	assert.Check(t, ok)
+	assert.Check(t, is.Equal(v, "1.44"))
+	assert.Check(t, is.Equal(calls, 1))
+}
+
+type negotiatorFunc func(ctx context.Context, host, clientVersion, pingVersion string) (string, error)
+
+func (f negotiatorFunc) Negotiate(ctx context.Context, host, clientVersion, pingVersion string) (string, error) {
+	return f(ctx, host, clientVersion, pingVersion)
+}
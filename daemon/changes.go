@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"errors"
+	"io"
 	"time"
 
 	"github.com/docker/docker/daemon/internal/metrics"
@@ -13,19 +14,98 @@ import (
 func (daemon *Daemon) ContainerChanges(ctx context.Context, name string) ([]archive.Change, error) {
 	start := time.Now()
 
-	container, err := daemon.GetContainer(name)
+	var changes []archive.Change
+	err := daemon.containerChanges(ctx, name, func(c archive.Change) error {
+		changes = append(changes, c)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	metrics.ContainerActions.WithValues("changes").UpdateSince(start)
+	return changes, nil
+}
+
+// ContainerChangesStream walks a container's filesystem changes and invokes
+// onChange for each one as it is discovered, never holding more than one
+// pending change in memory at a time. It backs the NDJSON variant of the
+// /containers/{name}/changes endpoint so a client diffing a container with a
+// very large change set doesn't make the daemon buffer the whole set (or the
+// client buffer the whole response) before anything can be written out.
+// This requires the image service to implement WalkChanges alongside its
+// existing batch Changes, invoking onChange as its own diff walk discovers
+// each change instead of returning a []archive.Change.
+func (daemon *Daemon) ContainerChangesStream(ctx context.Context, name string, onChange func(archive.Change) error) error {
+	start := time.Now()
 
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
 	if isWindows && container.IsRunning() {
+		return errors.New("Windows does not support diff of a running container")
+	}
+
+	if err := daemon.imageService.WalkChanges(ctx, container, onChange); err != nil {
+		return err
+	}
+	metrics.ContainerActions.WithValues("changes").UpdateSince(start)
+	return nil
+}
+
+// ContainerChangesTar returns a tar stream containing the contents of every
+// added or modified file in a container's filesystem diff, for the tar
+// variant of the /containers/{name}/changes endpoint. The caller must close
+// the returned ReadCloser.
+func (daemon *Daemon) ContainerChangesTar(ctx context.Context, name string) (io.ReadCloser, error) {
+	start := time.Now()
+
+	ctr, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+	if isWindows && ctr.IsRunning() {
 		return nil, errors.New("Windows does not support diff of a running container")
 	}
 
-	c, err := daemon.imageService.Changes(ctx, container)
+	changes, err := daemon.imageService.Changes(ctx, ctr)
+	if err != nil {
+		return nil, err
+	}
+
+	archiver, err := archive.ExportChanges(ctr.BaseFS, changes, nil, nil)
 	if err != nil {
 		return nil, err
 	}
 	metrics.ContainerActions.WithValues("changes").UpdateSince(start)
-	return c, nil
+	return archiver, nil
+}
+
+// containerChanges walks a container's filesystem changes, calling onChange
+// for each one after the full change set has been computed. It backs
+// ContainerChanges (the JSON format, which has to buffer the whole response
+// in memory regardless), unlike ContainerChangesStream, which calls the
+// image service's WalkChanges directly instead of going through here, so
+// that a large container's NDJSON diff doesn't also buffer the whole change
+// set before it can start streaming.
+func (daemon *Daemon) containerChanges(ctx context.Context, name string, onChange func(archive.Change) error) error {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	if isWindows && container.IsRunning() {
+		return errors.New("Windows does not support diff of a running container")
+	}
+
+	changes, err := daemon.imageService.Changes(ctx, container)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if err := onChange(c); err != nil {
+			return err
+		}
+	}
+	return nil
 }